@@ -0,0 +1,45 @@
+package qq
+
+import (
+	"context"
+	"sync"
+)
+
+// contextKey associates a context.Context key with the Field name it
+// should be logged under.
+type contextKey struct {
+	key  interface{}
+	name string
+}
+
+var (
+	contextKeysMu sync.Mutex
+	contextKeys   []contextKey
+)
+
+// RegisterContextKey registers key as one WithContext should pull out of a
+// context.Context, logging its value under name. It's meant to be called
+// from init, e.g. to propagate a trace ID or span ID carried on
+// request-scoped contexts.
+func RegisterContextKey(key interface{}, name string) {
+	contextKeysMu.Lock()
+	defer contextKeysMu.Unlock()
+	contextKeys = append(contextKeys, contextKey{key: key, name: name})
+}
+
+// WithContext returns a copy of l that prepends the registered context
+// keys found in ctx (see RegisterContextKey) to every Record it logs, the
+// same way With does. Keys not present in ctx are omitted.
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	contextKeysMu.Lock()
+	keys := append([]contextKey(nil), contextKeys...)
+	contextKeysMu.Unlock()
+
+	var keyvals []interface{}
+	for _, k := range keys {
+		if v := ctx.Value(k.key); v != nil {
+			keyvals = append(keyvals, k.name, v)
+		}
+	}
+	return l.With(keyvals...)
+}
@@ -0,0 +1,17 @@
+package qq
+
+// Flag bits control which fields TerminalFormat prints in a Record's
+// header, mirroring the flag model of the standard log package.
+const (
+	Ldate         = 1 << iota // the date in the local time zone: 2009/01/23
+	Ltime                     // the time in the local time zone: 01:23:23
+	Lmicroseconds             // microsecond resolution: 01:23:23.123456
+	Llongfile                 // full file path and line number: /a/b/c.go:23
+	Lshortfile                // final file name element and line number: c.go:23
+	LUTC                      // use UTC rather than the local time zone
+	Lfuncname                 // the calling function's name
+	LnoColor                  // disable ANSI color codes
+
+	// LstdFlags is the original qq header: time, short file, func name.
+	LstdFlags = Ltime | Lshortfile | Lfuncname
+)
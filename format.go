@@ -0,0 +1,158 @@
+package qq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Format renders a Record as bytes ready to write to a Handler's
+// destination, e.g. a terminal, a log aggregator, or syslog.
+type Format interface {
+	Format(r Record) []byte
+}
+
+type terminalFormat struct{}
+
+// TerminalFormat renders Records in qq's original style: an uncolored
+// "[15:04:05 file.go:23 main.main]" header (with LstdFlags) whenever the call
+// site or log group changes, followed by a colorized elapsed time and
+// name=value pairs.
+func TerminalFormat() Format {
+	return terminalFormat{}
+}
+
+func (terminalFormat) Format(r Record) []byte {
+	noColor := r.Flags&LnoColor != 0
+	wrap := func(text string, c color) string {
+		if noColor {
+			return text
+		}
+		return colorize(text, c)
+	}
+
+	var buf bytes.Buffer
+	if r.NewGroup {
+		if header := terminalHeader(r); header != "" {
+			fmt.Fprintf(&buf, "\n[%s]\n", header)
+		}
+	}
+
+	parts := make([]string, 0, len(r.Fields)+1)
+	parts = append(parts, wrap(fmt.Sprintf("%.3fs", r.Elapsed.Seconds()), yellow))
+	for _, f := range r.Fields {
+		val := wrap(fmt.Sprintf("%#v", f.Value), cyan)
+		if f.Name == noName {
+			parts = append(parts, val)
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s=%s", wrap(f.Name, bold), val))
+	}
+
+	buf.WriteString(strings.Join(parts, " "))
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+// terminalHeader builds the "15:04:05 file.go:23 main.main" header text from
+// r's flags, honoring Ldate, Ltime, Lmicroseconds, Llongfile, Lshortfile,
+// LUTC, and Lfuncname. Prefix, if set, comes first.
+func terminalHeader(r Record) string {
+	t := r.Time
+	if r.Flags&LUTC != 0 {
+		t = t.UTC()
+	}
+
+	var parts []string
+	if r.Prefix != "" {
+		parts = append(parts, r.Prefix)
+	}
+	if r.Flags&Ldate != 0 {
+		parts = append(parts, t.Format("2006/01/02"))
+	}
+	if r.Flags&Lmicroseconds != 0 {
+		parts = append(parts, t.Format("15:04:05.000000"))
+	} else if r.Flags&Ltime != 0 {
+		parts = append(parts, t.Format("15:04:05"))
+	}
+	if r.Flags&Llongfile != 0 {
+		parts = append(parts, fmt.Sprintf("%s:%d", r.File, r.Line))
+	} else if r.Flags&Lshortfile != 0 {
+		parts = append(parts, fmt.Sprintf("%s:%d", filepath.Base(r.File), r.Line))
+	}
+	if r.Flags&Lfuncname != 0 {
+		parts = append(parts, r.Func)
+	}
+	return strings.Join(parts, " ")
+}
+
+type logfmtFormat struct{}
+
+// LogfmtFormat renders Records as plain "k=v k2=v2" lines with no ANSI
+// color, suitable for files or log aggregators that parse logfmt.
+func LogfmtFormat() Format {
+	return logfmtFormat{}
+}
+
+func (logfmtFormat) Format(r Record) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "t=%s file=%s func=%s elapsed=%s",
+		r.Time.Format(time.RFC3339Nano), filepath.Base(r.File), r.Func, r.Elapsed)
+	for i, f := range r.Fields {
+		name := f.Name
+		if name == noName {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		fmt.Fprintf(&buf, " %s=%v", name, f.Value)
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
+
+type jsonFormat struct{}
+
+// JSONFormat renders Records as one JSON object per line, e.g.
+// {"t":"...","file":"/src/app/main.go","line":12,"func":"main.main",
+// "level":"info","elapsed":0.5,"fields":{"port":443}}, where file is the
+// full path.
+func JSONFormat() Format {
+	return jsonFormat{}
+}
+
+type jsonRecord struct {
+	Time    time.Time              `json:"t"`
+	File    string                 `json:"file"`
+	Line    int                    `json:"line"`
+	Func    string                 `json:"func"`
+	Level   string                 `json:"level"`
+	Elapsed float64                `json:"elapsed"`
+	Fields  map[string]interface{} `json:"fields"`
+}
+
+func (jsonFormat) Format(r Record) []byte {
+	fields := make(map[string]interface{}, len(r.Fields))
+	for i, f := range r.Fields {
+		name := f.Name
+		if name == noName {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		fields[name] = f.Value
+	}
+
+	b, err := json.Marshal(jsonRecord{
+		Time:    r.Time,
+		File:    r.File,
+		Line:    r.Line,
+		Func:    r.Func,
+		Level:   r.Level.String(),
+		Elapsed: r.Elapsed.Seconds(),
+		Fields:  fields,
+	})
+	if err != nil {
+		b = []byte(fmt.Sprintf(`{"error":%q}`, err.Error()))
+	}
+	return append(b, '\n')
+}
@@ -0,0 +1,204 @@
+package qq
+
+import (
+	"io"
+	"log/syslog"
+	"os"
+	"sync"
+)
+
+// isTerminal reports whether w is a terminal, so Handlers can auto-enable
+// LnoColor (disabling ANSI color) for destinations like files or pipes that
+// ANSI codes wouldn't help. It checks whether w is an *os.File open on a character device,
+// which is true for terminals and false for regular files and pipes —
+// the same stdlib-only check isatty-style tools used before
+// golang.org/x/term existed.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
+
+// Handler writes a Record somewhere: a file, an io.Writer, syslog, or some
+// combination of those. A Logger builds Records and hands them to its
+// Handler; the Handler decides what happens next.
+type Handler interface {
+	Log(r Record) error
+}
+
+// lazyFileWriter is an io.Writer that opens the file at path, appends to
+// it, and closes it again on every Write. It never holds the file open
+// between writes, so it's safe to use alongside other processes (or `qq`
+// itself run from multiple programs) appending to the same path.
+type lazyFileWriter struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (w *lazyFileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	f, err := os.OpenFile(w.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return f.Write(p)
+}
+
+// FileHandler returns a Handler that appends Records, rendered with
+// TerminalFormat, to the file at path, opening and closing it on every
+// write the way qq always has (now expressed as a StreamHandler over a
+// lazyFileWriter). A regular file is never a terminal, so isTerminal
+// auto-disables ANSI color for it; use StreamHandler directly with a
+// tty-backed io.Writer if you want colorized output.
+func FileHandler(path string) Handler {
+	return StreamHandler(&lazyFileWriter{path: path}, TerminalFormat())
+}
+
+type streamHandler struct {
+	mu     sync.Mutex
+	w      io.Writer
+	format Format
+}
+
+// StreamHandler returns a Handler that renders Records with format and
+// writes them to w, e.g. os.Stderr, a bytes.Buffer in tests, or a
+// gzip.Writer.
+func StreamHandler(w io.Writer, format Format) Handler {
+	return &streamHandler{w: w, format: format}
+}
+
+func (h *streamHandler) Log(r Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !isTerminal(h.w) {
+		r.Flags |= LnoColor
+	}
+	_, err := h.w.Write(h.format.Format(r))
+	return err
+}
+
+// setWriter retargets h at w, guarded by h's own mutex so it's safe to call
+// while Records are concurrently being logged.
+func (h *streamHandler) setWriter(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.w = w
+}
+
+type syslogHandler struct {
+	mu sync.Mutex
+	w  *syslog.Writer
+}
+
+// SyslogHandler returns a Handler that sends Records, rendered with
+// LogfmtFormat, to the syslog daemon at addr over network (e.g. "udp",
+// "tcp", or "" for the local syslog), tagged with tag.
+func SyslogHandler(network, addr, tag string) (Handler, error) {
+	w, err := syslog.Dial(network, addr, syslog.LOG_INFO, tag)
+	if err != nil {
+		return nil, err
+	}
+	return &syslogHandler{w: w}, nil
+}
+
+func (h *syslogHandler) Log(r Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	msg := string(LogfmtFormat().Format(r))
+	switch {
+	case r.Level >= LevelError:
+		return h.w.Err(msg)
+	case r.Level >= LevelWarn:
+		return h.w.Warning(msg)
+	case r.Level >= LevelDebug:
+		return h.w.Info(msg)
+	default:
+		return h.w.Debug(msg)
+	}
+}
+
+type multiHandler []Handler
+
+// MultiHandler returns a Handler that logs each Record to every one of
+// handlers, in order. It returns the first error encountered, if any, after
+// trying them all.
+func MultiHandler(handlers ...Handler) Handler {
+	return multiHandler(handlers)
+}
+
+func (hs multiHandler) Log(r Record) error {
+	var firstErr error
+	for _, h := range hs {
+		if err := h.Log(r); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+type levelFilterHandler struct {
+	level Level
+	h     Handler
+}
+
+// LevelFilterHandler returns a Handler that drops Records below lvl and
+// passes the rest through to h.
+func LevelFilterHandler(lvl Level, h Handler) Handler {
+	return &levelFilterHandler{level: lvl, h: h}
+}
+
+func (h *levelFilterHandler) Log(r Record) error {
+	if r.Level < h.level {
+		return nil
+	}
+	return h.h.Log(r)
+}
+
+type callerFilterHandler struct {
+	match func(r Record) bool
+	h     Handler
+}
+
+// CallerFilterHandler returns a Handler that passes a Record to h only if
+// match, given the Record's caller info (File, Func, Line), returns true.
+func CallerFilterHandler(match func(r Record) bool, h Handler) Handler {
+	return &callerFilterHandler{match: match, h: h}
+}
+
+func (h *callerFilterHandler) Log(r Record) error {
+	if !h.match(r) {
+		return nil
+	}
+	return h.h.Log(r)
+}
+
+type failoverHandler []Handler
+
+// FailoverHandler returns a Handler that tries each of handlers in order,
+// returning the first nil error. If every Handler errors, it returns the
+// last error.
+func FailoverHandler(handlers ...Handler) Handler {
+	return failoverHandler(handlers)
+}
+
+func (hs failoverHandler) Log(r Record) error {
+	var err error
+	for _, h := range hs {
+		if err = h.Log(r); err == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -0,0 +1,92 @@
+package qq
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestStreamHandlerLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	h := StreamHandler(&buf, LogfmtFormat())
+
+	err := h.Log(Record{
+		Func:   "main.main",
+		Fields: []Field{{Name: "port", Value: 443}},
+	})
+	if err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+
+	got := buf.String()
+	if !bytes.Contains([]byte(got), []byte("port=443")) {
+		t.Errorf("Log output = %q, want it to contain %q", got, "port=443")
+	}
+}
+
+func TestMultiHandlerFansOutToAll(t *testing.T) {
+	var a, b bytes.Buffer
+	h := MultiHandler(StreamHandler(&a, LogfmtFormat()), StreamHandler(&b, LogfmtFormat()))
+
+	if err := h.Log(Record{Fields: []Field{{Name: "k", Value: "v"}}}); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if a.Len() == 0 || b.Len() == 0 {
+		t.Errorf("MultiHandler didn't write to both handlers: a=%q b=%q", a.String(), b.String())
+	}
+}
+
+func TestLevelFilterHandlerDropsBelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	h := LevelFilterHandler(LevelWarn, StreamHandler(&buf, LogfmtFormat()))
+
+	if err := h.Log(Record{Level: LevelInfo}); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("LevelFilterHandler let an Info Record through a LevelWarn filter: %q", buf.String())
+	}
+
+	if err := h.Log(Record{Level: LevelError}); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("LevelFilterHandler dropped an Error Record that meets a LevelWarn filter")
+	}
+}
+
+type erroringHandler struct{ err error }
+
+func (h erroringHandler) Log(Record) error { return h.err }
+
+func TestFailoverHandlerTriesNextOnError(t *testing.T) {
+	var buf bytes.Buffer
+	failing := erroringHandler{err: errors.New("boom")}
+	h := FailoverHandler(failing, StreamHandler(&buf, LogfmtFormat()))
+
+	if err := h.Log(Record{}); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("FailoverHandler didn't fall through to the second Handler after the first errored")
+	}
+}
+
+func TestCallerFilterHandlerMatch(t *testing.T) {
+	var buf bytes.Buffer
+	h := CallerFilterHandler(func(r Record) bool { return r.File == "allowed.go" }, StreamHandler(&buf, LogfmtFormat()))
+
+	if err := h.Log(Record{File: "other.go"}); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("CallerFilterHandler let a non-matching Record through: %q", buf.String())
+	}
+
+	if err := h.Log(Record{File: "allowed.go"}); err != nil {
+		t.Fatalf("Log: %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("CallerFilterHandler dropped a matching Record")
+	}
+}
@@ -0,0 +1,86 @@
+package qq
+
+import (
+	"fmt"
+	"os"
+)
+
+func init() {
+	if lvl, ok := ParseLevel(os.Getenv("QQ_LEVEL")); ok {
+		std.SetLevel(lvl)
+	}
+}
+
+// Log pretty-prints the given arguments through std, the package's default
+// Logger, at LevelInfo.
+func Log(a ...interface{}) {
+	std.log(LevelInfo, a)
+}
+
+// Debug pretty-prints the given arguments through std at LevelDebug.
+func Debug(a ...interface{}) {
+	std.log(LevelDebug, a)
+}
+
+// Info pretty-prints the given arguments through std at LevelInfo.
+func Info(a ...interface{}) {
+	std.log(LevelInfo, a)
+}
+
+// Warn pretty-prints the given arguments through std at LevelWarn.
+func Warn(a ...interface{}) {
+	std.log(LevelWarn, a)
+}
+
+// Error pretty-prints the given arguments through std at LevelError.
+func Error(a ...interface{}) {
+	std.log(LevelError, a)
+}
+
+// Fatal pretty-prints the given arguments through std at LevelFatal, then
+// calls os.Exit(1).
+func Fatal(a ...interface{}) {
+	std.log(LevelFatal, a)
+	os.Exit(1)
+}
+
+// Panic pretty-prints the given arguments through std at LevelFatal, then
+// panics with the formatted message.
+func Panic(a ...interface{}) {
+	std.log(LevelFatal, a)
+	panic(fmt.Sprint(a...))
+}
+
+// Debug pretty-prints the given arguments through l's Handler at LevelDebug.
+func (l *Logger) Debug(a ...interface{}) {
+	l.log(LevelDebug, a)
+}
+
+// Info pretty-prints the given arguments through l's Handler at LevelInfo.
+func (l *Logger) Info(a ...interface{}) {
+	l.log(LevelInfo, a)
+}
+
+// Warn pretty-prints the given arguments through l's Handler at LevelWarn.
+func (l *Logger) Warn(a ...interface{}) {
+	l.log(LevelWarn, a)
+}
+
+// Error pretty-prints the given arguments through l's Handler at LevelError.
+func (l *Logger) Error(a ...interface{}) {
+	l.log(LevelError, a)
+}
+
+// Fatal pretty-prints the given arguments through l's Handler at
+// LevelFatal, then calls os.Exit(1).
+func (l *Logger) Fatal(a ...interface{}) {
+	l.log(LevelFatal, a)
+	os.Exit(1)
+}
+
+// Panic pretty-prints the given arguments through l's Handler at
+// LevelFatal, then panics with the formatted message.
+func (l *Logger) Panic(a ...interface{}) {
+	l.log(LevelFatal, a)
+	panic(fmt.Sprint(a...))
+}
@@ -0,0 +1,57 @@
+package qq
+
+import "strings"
+
+// Level represents the severity of a log Record.
+type Level int
+
+const (
+	LevelTrace Level = iota
+	LevelDebug
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelFatal
+)
+
+// String returns the lowercase name of the level, e.g. "debug".
+func (l Level) String() string {
+	switch l {
+	case LevelTrace:
+		return "trace"
+	case LevelDebug:
+		return "debug"
+	case LevelInfo:
+		return "info"
+	case LevelWarn:
+		return "warn"
+	case LevelError:
+		return "error"
+	case LevelFatal:
+		return "fatal"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseLevel parses the case-insensitive level name s (e.g. "debug",
+// "WARN") into a Level. It returns false if s isn't a recognized level
+// name.
+func ParseLevel(s string) (Level, bool) {
+	switch strings.ToLower(s) {
+	case "trace":
+		return LevelTrace, true
+	case "debug":
+		return LevelDebug, true
+	case "info":
+		return LevelInfo, true
+	case "warn":
+		return LevelWarn, true
+	case "error":
+		return LevelError, true
+	case "fatal":
+		return LevelFatal, true
+	default:
+		return 0, false
+	}
+}
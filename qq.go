@@ -2,13 +2,11 @@ package qq
 
 import (
 	"bytes"
-	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/printer"
 	"go/token"
-	"os"
-	"path/filepath"
+	"io"
 	"runtime"
 	"sync"
 	"time"
@@ -25,112 +23,268 @@ const (
 	noName = ""
 )
 
-// A Logger writes pretty log messages to a file. Loggers write to files only,
-// not io.Writers. The upside of this restriction is you don't have to open
-// and close log files yourself. Loggers do that for you. Loggers are safe for
-// concurrent use.
+// A Logger builds a Record for every call and hands it to a Handler, which
+// decides where it ends up. New creates a Logger backed by a FileHandler so
+// most callers don't need to think about Handlers at all. Loggers are safe
+// for concurrent use.
 type Logger struct {
 	mu       sync.Mutex
-	path     string
+	handler  Handler
+	level    Level
+	flags    int
+	prefix   string
+	context  []Field // set by With/WithContext; prepended to every Record's Fields
+	path     string  // set by New; sugar for Path()
 	start    time.Time
 	timer    *time.Timer
-	lastFile string // for determining when to print header
+	lastFile string // for determining when a new log group starts
 	lastFunc string
 }
 
-// TODO: implement flag that controls what gets printed in the header
+// std is the default Logger used by the package-level Log, Debug, Info, and
+// friends.
+var std = New("qq.log")
 
-// New creates a Logger that writes to the file at the given path.
+// New creates a Logger that writes to the file at the given path. It's
+// equivalent to NewWriter(&lazyFileWriter{path}), which opens and closes
+// the file on every write rather than holding it open, so callers don't
+// have to manage the file themselves.
 func New(path string) *Logger {
+	l := NewWriter(&lazyFileWriter{path: path})
+	l.path = path
+	return l
+}
+
+// NewWriter creates a Logger that writes to w, e.g. os.Stderr, a
+// bytes.Buffer in tests, a gzip.Writer, or a rotating writer like
+// lumberjack.Logger.
+func NewWriter(w io.Writer) *Logger {
+	return NewHandler(StreamHandler(w, TerminalFormat()))
+}
+
+// NewHandler creates a Logger that hands its Records to h, e.g. a
+// MultiHandler fanning out to a SyslogHandler and a FailoverHandler, or any
+// other composition of the built-in Handlers.
+func NewHandler(h Handler) *Logger {
 	t := time.NewTimer(0)
 	t.Stop()
 
 	return &Logger{
-		path:  path,
-		timer: t,
+		handler: h,
+		flags:   LstdFlags,
+		timer:   t,
 	}
 }
 
-// Log pretty-prints the given arguments to the file associated with the Logger.
+// Log pretty-prints the given arguments through the Logger's Handler.
 func (l *Logger) Log(a ...interface{}) {
+	l.log(LevelInfo, a)
+}
+
+// Path retuns the full path to the file associated with the Logger, or ""
+// if the Logger wasn't created with New.
+func (l *Logger) Path() string {
 	l.mu.Lock()
 	defer l.mu.Unlock()
+	return l.path
+}
 
-	// will print line break if more than 2s since last write (groups logs
-	// together)
-	timerExpired := !l.timer.Reset(2 * time.Second)
-	if timerExpired {
-		l.start = time.Now()
+// SetLevel sets the minimum Level the Logger will pass to its Handler.
+// Calls below lvl are dropped before their source text is parsed, so
+// filtered-out calls don't pay for argNames. The zero value, LevelTrace,
+// logs everything.
+func (l *Logger) SetLevel(lvl Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.level = lvl
+}
+
+// Level returns the Logger's current minimum Level.
+func (l *Logger) Level() Level {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.level
+}
+
+// SetFlags sets the header flags the Logger passes to its Handler, e.g.
+// Ldate|Ltime|Lshortfile. See the Lxxx constants.
+func (l *Logger) SetFlags(flags int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.flags = flags
+}
+
+// Flags returns the Logger's current header flags.
+func (l *Logger) Flags() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.flags
+}
+
+// SetPrefix sets a string to print inside the header brackets, ahead of the
+// other header fields.
+func (l *Logger) SetPrefix(prefix string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prefix = prefix
+}
+
+// Prefix returns the Logger's current prefix.
+func (l *Logger) Prefix() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.prefix
+}
+
+// SetOutput retargets the Logger at w, e.g. to redirect to os.Stderr when
+// running under `go test -v`. If the Logger's Handler is a StreamHandler
+// (as with New or NewWriter), SetOutput just swaps its underlying writer and
+// keeps its Format; otherwise it replaces the Logger's Handler outright with
+// a StreamHandler over w using TerminalFormat.
+func (l *Logger) SetOutput(w io.Writer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.path = noName
+	if sh, ok := l.handler.(*streamHandler); ok {
+		sh.setWriter(w)
+		return
 	}
+	l.handler = StreamHandler(w, TerminalFormat())
+}
 
-	// get info about func calling qq.Log()
-	var skip int // num levels up the call stack
-	if l == std {
-		skip = 2 // user is calling qq.Log()
-	} else {
-		skip = 1 // user is calling myCustomQQLogger.Log()
+// SetHandler retargets the Logger at h, e.g. a MultiHandler fanning out to
+// a SyslogHandler and a FailoverHandler of file Handlers.
+func (l *Logger) SetHandler(h Handler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.path = noName
+	l.handler = h
+}
+
+// With returns a copy of l that prepends the given alternating key/value
+// pairs to every Record it logs, ahead of the name=value pairs extracted
+// from the call's own arguments. Keys are expected to be strings, e.g.
+// l.With("component", "api", "request_id", id).
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	ctx := make([]Field, len(l.context), len(l.context)+len(keyvals)/2)
+	copy(ctx, l.context)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		name, _ := keyvals[i].(string)
+		ctx = append(ctx, Field{Name: name, Value: keyvals[i+1]})
 	}
+
+	t := time.NewTimer(0)
+	t.Stop()
+
+	return &Logger{
+		handler: l.handler,
+		level:   l.level,
+		flags:   l.flags,
+		prefix:  l.prefix,
+		context: ctx,
+		path:    l.path,
+		timer:   t,
+	}
+}
+
+// log builds a Record describing the calling code and the given arguments,
+// then hands it to l.handler.
+func (l *Logger) log(lvl Level, a []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// dropped before argNames (a full parser.ParseFile) runs, so filtered
+	// calls are cheap
+	if lvl < l.level {
+		return
+	}
+
+	// a new group starts if more than 2s have passed since the last write
+	newGroup := !l.timer.Reset(2 * time.Second)
+	if newGroup {
+		l.start = time.Now()
+	}
+
+	// get info about func calling qq.Log() (or qq.Debug(), qq.Info(), ...).
+	// Every entry point, std's package-level funcs included, is a single
+	// wrapper frame that calls l.log(), which calls runtime.Caller(), so the
+	// skip count is the same regardless of which Logger this is.
+	const skip = 2 // user -> qq.Log() or myCustomQQLogger.Log() -> l.log() -> runtime.Caller()
 	pc, filename, line, ok := runtime.Caller(skip)
 	if !ok {
-		l.Output(a...) // no fancy printing :(
+		l.handler.Log(Record{
+			Time:     time.Now(),
+			Level:    lvl,
+			Elapsed:  time.Since(l.start),
+			NewGroup: newGroup,
+			Flags:    l.flags,
+			Prefix:   l.prefix,
+			Fields:   l.fields(nil, a),
+		})
 		return
 	}
 
 	// print header if necessary, e.g. [14:00:36 main.go main.main]
 	funcName := runtime.FuncForPC(pc).Name()
-	if timerExpired || funcName != l.lastFunc || filename != l.lastFile {
-		l.lastFunc = funcName
-		l.lastFile = filename
-		l.printHeader()
+	if funcName != l.lastFunc || filename != l.lastFile {
+		newGroup = true
 	}
+	l.lastFunc = funcName
+	l.lastFile = filename
 
-	// extract arg names from source text between parens in qq.Log()
+	// extract arg names from source text between parens in the call
 	names, err := argNames(filename, line)
 	if err != nil {
-		l.Output(a...) // no fancy printing :(
+		l.handler.Log(Record{
+			Time:     time.Now(),
+			PC:       pc,
+			File:     filename,
+			Line:     line,
+			Func:     funcName,
+			Level:    lvl,
+			Elapsed:  time.Since(l.start),
+			NewGroup: newGroup,
+			Flags:    l.flags,
+			Prefix:   l.prefix,
+			Fields:   l.fields(nil, a),
+		})
 		return
 	}
 
-	// colorize names and values. convert values to %#v strings
-	a = formatArgs(names, a)
-	l.Output(a...)
-}
-
-// Path retuns the full path to the file associated with the Logger.
-func (l *Logger) Path() string {
-	return l.path
+	l.handler.Log(Record{
+		Time:     time.Now(),
+		PC:       pc,
+		File:     filename,
+		Line:     line,
+		Func:     funcName,
+		Level:    lvl,
+		Elapsed:  time.Since(l.start),
+		NewGroup: newGroup,
+		Flags:    l.flags,
+		Prefix:   l.prefix,
+		Fields:   l.fields(names, a),
+	})
 }
 
-// open returns a file descriptor for the file at l.path, creating it if it
-// doesn't exist. It will panic if it can't open the file.
-func (l *Logger) open() *os.File {
-	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
-	if err != nil {
-		panic(err)
+// fields prepends l's context Fields (set via With/WithContext) to the
+// Fields extracted by pairing up names with values. If names is shorter
+// than values (or nil), the remaining Fields have an empty Name.
+func (l *Logger) fields(names []string, values []interface{}) []Field {
+	fs := make([]Field, len(l.context), len(l.context)+len(values))
+	copy(fs, l.context)
+	for i, v := range values {
+		var name string
+		if i < len(names) {
+			name = names[i]
+		}
+		fs = append(fs, Field{Name: name, Value: v})
 	}
-	return f
-}
-
-// Output writes to the log file associated with l. Each log message is
-// prepended with a timestamp.
-func (l *Logger) Output(a ...interface{}) {
-	timestamp := fmt.Sprintf("%.3fs", time.Since(l.start).Seconds())
-	timestamp = colorize(timestamp, yellow)
-	a = append([]interface{}{timestamp}, a...)
-	f := l.open()
-	defer f.Close()
-	fmt.Fprintln(f, a...)
-}
-
-// printHeader prints a header of the form [16:11:18 main.go main.main]. Headers
-// make logs easier to read by reducing redundant information that is normally
-// printed on each line.
-func (l *Logger) printHeader() {
-	shortFile := filepath.Base(std.lastFile)
-	t := time.Now().Format("15:04:05")
-	f := l.open()
-	defer f.Close()
-	fmt.Fprintf(f, "\n[%s %s %s]\n", t, shortFile, std.lastFunc)
+	return fs
 }
 
 // argNames finds the qq.Log() call at the given filename/line number and
@@ -171,20 +325,35 @@ func argNames(filename string, line int) ([]string, error) {
 	return names, nil
 }
 
-// qqCall returns true if the given function call expression is for a qq
-// function, e.g. qq.Log().
+// levelHelperNames holds the names of the qq functions whose arguments
+// argNames knows how to extract: the original Log, plus the per-level
+// helpers.
+var levelHelperNames = map[string]bool{
+	"Log":   true,
+	"Debug": true,
+	"Info":  true,
+	"Warn":  true,
+	"Error": true,
+	"Fatal": true,
+	"Panic": true,
+}
+
+// qqCall returns true if the given function call expression looks like a
+// qq logging call: a method named Log, Debug, Info, Warn, Error, Fatal, or
+// Panic invoked on any receiver, e.g. qq.Log(), logger.Info(), or
+// base.With("component", "api").Debug(). argNames runs without type
+// information, so it can't confirm the receiver is actually a *qq.Logger —
+// a type with a same-named method would also match. That's an acceptable
+// false positive for a debug aid: the worst case is a stray name=value
+// pair, and the caller already falls back to an empty Name when the
+// source can't be parsed at all.
 func qqCall(n *ast.CallExpr) bool {
 	sel, is := n.Fun.(*ast.SelectorExpr) // SelectorExpr example: a.B()
 	if !is {
 		return false
 	}
 
-	ident, is := sel.X.(*ast.Ident) // sel.X is the part that precedes the .
-	if !is {
-		return false
-	}
-
-	return ident.Name == "qq"
+	return levelHelperNames[sel.Sel.Name]
 }
 
 // argName returns the source text of the given argument if it's a variable or
@@ -218,27 +387,6 @@ func exprToString(arg ast.Expr) string {
 	return buf.String() // returns empty string if printer fails
 }
 
-// formatArgs turns a slice of arguments into pretty-printed strings. If the
-// argument is a variable or an expression, it will be returned as a
-// name=value string, e.g. "port=443", "3+2=5". Variable names, expressions, and
-// values are colorized using ANSI escape codes.
-func formatArgs(names []string, values []interface{}) []interface{} {
-	formatted := make([]interface{}, len(values))
-	for i := 0; i < len(values); i++ {
-		val := fmt.Sprintf("%#v", values[i])
-		val = colorize(val, cyan)
-
-		if names[i] == "" {
-			// arg is a literal
-			formatted[i] = val
-		} else {
-			name := colorize(names[i], bold)
-			formatted[i] = fmt.Sprintf("%s=%s", name, val)
-		}
-	}
-	return formatted
-}
-
 // colorize returns the given text encapsulated in ANSI escape codes that
 // give the text a color in the terminal.
 func colorize(text string, c color) string {
@@ -0,0 +1,72 @@
+package qq
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLoggerFieldNamesFromNamedVar(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewWriter(&buf)
+	logger.SetFlags(0) // no header noise
+
+	userID := 7
+	logger.Info(userID)
+
+	got := buf.String()
+	if !strings.Contains(got, "userID=7") {
+		t.Errorf("Info(userID) = %q, want it to contain %q", got, "userID=7")
+	}
+}
+
+func TestLoggerFieldNamesAfterWith(t *testing.T) {
+	var buf bytes.Buffer
+	base := NewWriter(&buf)
+	base.SetFlags(0)
+
+	logger := base.With("component", "api")
+	userID := 7
+	logger.Info(userID)
+
+	got := buf.String()
+	if !strings.Contains(got, "component=") || !strings.Contains(got, "api") {
+		t.Errorf("Info(userID) = %q, want the With context field present", got)
+	}
+	if !strings.Contains(got, "userID=7") {
+		t.Errorf("Info(userID) = %q, want it to contain %q", got, "userID=7")
+	}
+}
+
+func TestLoggerFieldNameEmptyForLiteral(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewWriter(&buf)
+	logger.SetFlags(0)
+
+	logger.Info(5)
+
+	got := buf.String()
+	if strings.Contains(got, "=5") {
+		t.Errorf("Info(5) = %q, literal args shouldn't get a name=value pair", got)
+	}
+	if !strings.Contains(got, "5") {
+		t.Errorf("Info(5) = %q, want the literal value logged", got)
+	}
+}
+
+func TestSetLevelFiltersBelowThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewWriter(&buf)
+	logger.SetFlags(0)
+	logger.SetLevel(LevelWarn)
+
+	logger.Info("dropped")
+	if buf.Len() != 0 {
+		t.Fatalf("Info logged below LevelWarn threshold: %q", buf.String())
+	}
+
+	logger.Warn("kept")
+	if buf.Len() == 0 {
+		t.Fatal("Warn was filtered out despite meeting the threshold")
+	}
+}
@@ -0,0 +1,30 @@
+package qq
+
+import "time"
+
+// Field is a single name/value pair extracted from the source text of a
+// Logger call, e.g. Log(port) yields Field{Name: "port", Value: port}. Fields
+// extracted from literals or from args whose source text couldn't be
+// recovered have an empty Name.
+type Field struct {
+	Name  string
+	Value interface{}
+}
+
+// Record is everything a Handler needs to render one log line: when and
+// where the call happened, how long since the current log group started,
+// and the Fields extracted from the call's arguments. Loggers build Records;
+// Handlers and Formats consume them.
+type Record struct {
+	Time     time.Time
+	PC       uintptr
+	File     string
+	Line     int
+	Func     string
+	Level    Level
+	Elapsed  time.Duration
+	NewGroup bool   // true if this Record starts a new group of log lines
+	Flags    int    // header flags, e.g. Ldate|Ltime, see Logger.SetFlags
+	Prefix   string // see Logger.SetPrefix
+	Fields   []Field
+}